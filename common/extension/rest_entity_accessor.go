@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"github.com/apache/dubbo-go/protocol/rest/server"
+)
+
+// entityAccessors holds the server.EntityReaderWriter registered for each
+// MIME type, e.g. "application/json" or "application/xml".
+var entityAccessors = make(map[string]server.EntityReaderWriter, 4)
+
+// SetRestEntityAccessor registers accessor as the reader/writer for mimeType.
+// Registering again for a MIME type that already has one overwrites it, so
+// applications can swap out the built-in accessors with a custom codec.
+func SetRestEntityAccessor(mimeType string, accessor server.EntityReaderWriter) {
+	entityAccessors[mimeType] = accessor
+}
+
+// GetRestEntityAccessor returns the server.EntityReaderWriter registered for
+// mimeType, or nil if none was registered.
+func GetRestEntityAccessor(mimeType string) server.EntityReaderWriter {
+	return entityAccessors[mimeType]
+}
@@ -0,0 +1,34 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"reflect"
+)
+
+import (
+	"github.com/apache/dubbo-go/protocol/rest/server"
+)
+
+// SetRestParamConverter registers converter to parse path/query/header
+// values into kind, for argument types assembleArgsFromPathParams/
+// QueryParams/Headers can't bind out of the box (UUID, decimal, etc.).
+// Registering again for kind overwrites the previous converter.
+func SetRestParamConverter(kind reflect.Kind, converter func(raw string) (interface{}, error)) {
+	server.RegisterParamConverter(kind, converter)
+}
@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"strings"
+)
+
+// RestMethodConfig is the configuration of one RPC method exposed over REST.
+// It describes the HTTP route the method is reachable on, and how path
+// parameters, query parameters, headers and the body map onto the method's
+// arguments.
+type RestMethodConfig struct {
+	InterfaceName string
+	MethodName    string
+	// Path is the URL path the method is deployed on, e.g. "/users/{id}".
+	Path string
+	// MethodType is the HTTP verb (GET/POST/PUT/DELETE/PATCH/HEAD/OPTIONS) the
+	// method is served on. Together with Path it forms the route identity, so
+	// the same Path can be deployed more than once as long as MethodType
+	// differs.
+	MethodType string
+	// Consumes is the MIME type(s) the method accepts in the request body,
+	// e.g. "application/json".
+	Consumes string
+	// Produces is the MIME type(s) the method writes the response entity as.
+	Produces string
+
+	PathParamsMap  map[int]string
+	QueryParamsMap map[int]string
+	HeadersMap     map[int]string
+	Body           int
+
+	// EnableContentEncoding mirrors RestServiceConfig.EnableContentEncoding
+	// for this method, resolved at config-load time.
+	EnableContentEncoding bool
+	// MinCompressSize mirrors RestServiceConfig.MinCompressSize for this
+	// method, resolved at config-load time.
+	MinCompressSize int
+
+	// Cors mirrors RestServiceConfig.Cors for this method, resolved at
+	// config-load time. Nil means CORS is disabled for this route. Don't
+	// also use GoRestfulServer.EnableCors on the same server: its filter
+	// answers CORS preflight requests before a route's own filter runs, so
+	// this field never takes effect on a preflight request to such a route.
+	Cors *CorsConfig
+
+	// ParamLayouts maps an arg index to the time.Layout used to parse it
+	// when the arg is a time.Time bound from a path param, query param or
+	// header. An arg index missing from the map parses with time.RFC3339.
+	ParamLayouts map[int]string
+}
+
+// HTTPMethod returns MethodType upper-cased, defaulting to "GET" when unset.
+// Deploy/UnDeploy/RouteKey must all resolve the default through this method
+// so they agree on the identity of a route that didn't set MethodType.
+func (c *RestMethodConfig) HTTPMethod() string {
+	if c.MethodType == "" {
+		return "GET"
+	}
+	return strings.ToUpper(c.MethodType)
+}
+
+// RouteKey returns the identity used to tell routes deployed on the same
+// RestServer apart. Two RestMethodConfigs with the same RouteKey cannot be
+// deployed at the same time.
+func (c *RestMethodConfig) RouteKey() string {
+	return c.HTTPMethod() + " " + c.Path
+}
+
+// defaultMinCompressSize is used when a RestServiceConfig enables content
+// encoding without setting MinCompressSize.
+const defaultMinCompressSize = 1024
+
+// RestServiceConfig is the configuration of a service exposed over REST,
+// grouping the RestMethodConfigs it deploys along with settings that apply
+// to all of them.
+type RestServiceConfig struct {
+	InterfaceName string
+	// EnableContentEncoding turns on gzip/deflate response compression for
+	// every method of this service, when the client sends Accept-Encoding.
+	EnableContentEncoding bool
+	// MinCompressSize is the minimum response size, in bytes, worth
+	// compressing. Defaults to defaultMinCompressSize when <= 0.
+	MinCompressSize int
+	// Cors configures CORS handling for every method of this service. Nil
+	// disables CORS.
+	Cors              *CorsConfig
+	RestMethodConfigs []*RestMethodConfig
+}
+
+// MinCompressSizeOrDefault returns MinCompressSize, or defaultMinCompressSize
+// if it wasn't set.
+func (c *RestServiceConfig) MinCompressSizeOrDefault() int {
+	if c.MinCompressSize <= 0 {
+		return defaultMinCompressSize
+	}
+	return c.MinCompressSize
+}
+
+// ResolveMethodConfigs propagates this service's EnableContentEncoding,
+// MinCompressSize and Cors onto each of RestMethodConfigs, so enabling
+// compression or CORS for a whole service doesn't require repeating the
+// setting on every RestMethodConfig. A method's own EnableContentEncoding is
+// left untouched if already true, and its own Cors is left untouched if
+// already set, so per-method overrides still take precedence. Call this once
+// after loading the config, before deploying any of RestMethodConfigs.
+func (c *RestServiceConfig) ResolveMethodConfigs() {
+	for _, m := range c.RestMethodConfigs {
+		if c.EnableContentEncoding {
+			m.EnableContentEncoding = true
+		}
+		if m.MinCompressSize <= 0 {
+			m.MinCompressSize = c.MinCompressSizeOrDefault()
+		}
+		if m.Cors == nil {
+			m.Cors = c.Cors
+		}
+	}
+}
+
+// CorsConfig configures cross-origin resource sharing for a REST service or
+// method. It is set per-service via RestServiceConfig.Cors, which a
+// RestMethodConfig.Cors overrides per-method, or for every route of a server
+// via GoRestfulServer.EnableCors, which a route's own Cors does not override
+// on preflight requests.
+type CorsConfig struct {
+	// AllowedDomains lists allowed Origin values. Entries are matched as
+	// regular expressions, except for the literal "*" which allows any
+	// origin.
+	AllowedDomains []string
+	// AllowedHeaders is echoed back as Access-Control-Allow-Headers on
+	// preflight responses.
+	AllowedHeaders []string
+	// AllowedMethods is echoed back as Access-Control-Allow-Methods on
+	// preflight responses.
+	AllowedMethods []string
+	// ExposeHeaders is sent as Access-Control-Expose-Headers on every
+	// allowed response.
+	ExposeHeaders []string
+	// AllowCredentials sets Access-Control-Allow-Credentials: true when set.
+	AllowCredentials bool
+	// MaxAge is the Access-Control-Max-Age, in seconds, sent on preflight
+	// responses. Not sent when <= 0.
+	MaxAge int
+}
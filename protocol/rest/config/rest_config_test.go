@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveMethodConfigsPropagatesServiceDefaults(t *testing.T) {
+	cors := &CorsConfig{AllowedDomains: []string{"*"}}
+	svc := &RestServiceConfig{
+		EnableContentEncoding: true,
+		Cors:                  cors,
+		RestMethodConfigs: []*RestMethodConfig{
+			{MethodName: "GetUser"},
+		},
+	}
+
+	svc.ResolveMethodConfigs()
+
+	m := svc.RestMethodConfigs[0]
+	assert.True(t, m.EnableContentEncoding)
+	assert.Equal(t, defaultMinCompressSize, m.MinCompressSize)
+	assert.Same(t, cors, m.Cors)
+}
+
+func TestResolveMethodConfigsDoesNotOverrideMethodSettings(t *testing.T) {
+	methodCors := &CorsConfig{AllowedDomains: []string{"example.com"}}
+	svc := &RestServiceConfig{
+		EnableContentEncoding: false,
+		MinCompressSize:       2048,
+		Cors:                  &CorsConfig{AllowedDomains: []string{"*"}},
+		RestMethodConfigs: []*RestMethodConfig{
+			{
+				MethodName:            "GetUser",
+				EnableContentEncoding: true,
+				MinCompressSize:       4096,
+				Cors:                  methodCors,
+			},
+		},
+	}
+
+	svc.ResolveMethodConfigs()
+
+	m := svc.RestMethodConfigs[0]
+	assert.True(t, m.EnableContentEncoding)
+	assert.Equal(t, 4096, m.MinCompressSize)
+	assert.Same(t, methodCors, m.Cors)
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	rest_config "github.com/apache/dubbo-go/protocol/rest/config"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// ParamConverter parses a raw path/query/header value into a Go value of a
+// type assembleArgsFrom* doesn't bind out of the box (UUID, decimal, etc.).
+type ParamConverter func(raw string) (interface{}, error)
+
+// paramConverters holds the ParamConverter registered for each reflect.Kind.
+// extension.SetRestParamConverter is the public entry point applications use
+// to populate it; it lives here, rather than in common/extension itself, to
+// avoid an import cycle (common/extension already imports this package for
+// EntityReaderWriter).
+var paramConverters = make(map[reflect.Kind]ParamConverter, 4)
+
+// RegisterParamConverter registers converter for kind, overwriting any
+// previous converter for kind.
+func RegisterParamConverter(kind reflect.Kind, converter ParamConverter) {
+	paramConverters[kind] = converter
+}
+
+// GetParamConverter returns the ParamConverter registered for kind, or nil.
+func GetParamConverter(kind reflect.Kind) ParamConverter {
+	return paramConverters[kind]
+}
+
+// layoutFor returns the time.Layout configured for argIndex via
+// methodConfig.ParamLayouts, or "" if none was set.
+func layoutFor(methodConfig *rest_config.RestMethodConfig, argIndex int) string {
+	if methodConfig.ParamLayouts == nil {
+		return ""
+	}
+	return methodConfig.ParamLayouts[argIndex]
+}
+
+// convertParam parses raw into a value of type t (unwrapping t if it's a
+// pointer), trying, in order: time.Time via layout (time.RFC3339 if layout is
+// ""), t's own encoding.TextUnmarshaler, the builtin string/bool/int/
+// uint/float kinds, and finally any converter registered with
+// RegisterParamConverter for t's kind. time.Time is checked before
+// TextUnmarshaler because time.Time implements that interface itself
+// (hardcoded to RFC3339), which would otherwise shadow the layout option.
+func convertParam(raw string, t reflect.Type, layout string) (interface{}, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return time.Parse(layout, raw)
+	}
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		v := reflect.New(t)
+		if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return nil, err
+		}
+		return v.Elem().Interface(), nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	case reflect.Int:
+		return strconv.Atoi(raw)
+	case reflect.Int32:
+		i64, err := strconv.ParseInt(raw, 10, 32)
+		return int32(i64), err
+	case reflect.Int64:
+		return strconv.ParseInt(raw, 10, 64)
+	case reflect.Uint:
+		u64, err := strconv.ParseUint(raw, 10, 64)
+		return uint(u64), err
+	case reflect.Uint32:
+		u64, err := strconv.ParseUint(raw, 10, 32)
+		return uint32(u64), err
+	case reflect.Uint64:
+		return strconv.ParseUint(raw, 10, 64)
+	case reflect.Float32:
+		f64, err := strconv.ParseFloat(raw, 32)
+		return float32(f64), err
+	case reflect.Float64:
+		return strconv.ParseFloat(raw, 64)
+	}
+	if converter := GetParamConverter(t.Kind()); converter != nil {
+		return converter(raw)
+	}
+	return nil, perrors.Errorf("no converter registered for type %v", t)
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertParamBuiltinKinds(t *testing.T) {
+	var (
+		boolVal    bool
+		uintVal    uint
+		uint32Val  uint32
+		uint64Val  uint64
+		float32Val float32
+		float64Val float64
+	)
+	tests := []struct {
+		name string
+		raw  string
+		typ  reflect.Type
+		want interface{}
+	}{
+		{"bool", "true", reflect.TypeOf(boolVal), true},
+		{"uint", "42", reflect.TypeOf(uintVal), uint(42)},
+		{"uint32", "42", reflect.TypeOf(uint32Val), uint32(42)},
+		{"uint64", "42", reflect.TypeOf(uint64Val), uint64(42)},
+		{"float32", "3.5", reflect.TypeOf(float32Val), float32(3.5)},
+		{"float64", "3.5", reflect.TypeOf(float64Val), float64(3.5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertParam(tt.raw, tt.typ, "")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertParamTimeUsesConfiguredLayout(t *testing.T) {
+	timeType := reflect.TypeOf(time.Time{})
+
+	got, err := convertParam("2026-07-27", timeType, "2006-01-02")
+	assert.NoError(t, err)
+	want, _ := time.Parse("2006-01-02", "2026-07-27")
+	assert.Equal(t, want, got)
+
+	// Empty layout falls back to RFC3339, not the TextUnmarshaler behavior
+	// time.Time also happens to implement.
+	got, err = convertParam("2026-07-27T10:00:00Z", timeType, "")
+	assert.NoError(t, err)
+	want, _ = time.Parse(time.RFC3339, "2026-07-27T10:00:00Z")
+	assert.Equal(t, want, got)
+
+	_, err = convertParam("2026-07-27", timeType, "")
+	assert.Error(t, err, "a date-only value must not parse under the RFC3339 default")
+}
+
+type testID struct {
+	value string
+}
+
+func (id *testID) UnmarshalText(b []byte) error {
+	id.value = string(b)
+	return nil
+}
+
+func TestConvertParamTextUnmarshaler(t *testing.T) {
+	got, err := convertParam("abc-123", reflect.TypeOf(testID{}), "")
+	assert.NoError(t, err)
+	assert.Equal(t, testID{value: "abc-123"}, got)
+}
+
+func TestConvertParamCustomConverter(t *testing.T) {
+	type money struct {
+		cents int
+	}
+	RegisterParamConverter(reflect.Struct, func(raw string) (interface{}, error) {
+		return money{cents: len(raw)}, nil
+	})
+	defer delete(paramConverters, reflect.Struct)
+
+	got, err := convertParam("12.34", reflect.TypeOf(money{}), "")
+	assert.NoError(t, err)
+	assert.Equal(t, money{cents: len("12.34")}, got)
+}
@@ -19,9 +19,9 @@ package server
 
 import (
 	"context"
+	"mime"
 	"net/http"
 	"reflect"
-	"strconv"
 	"strings"
 )
 
@@ -40,9 +40,11 @@ import (
 type RestServer interface {
 	// start rest server
 	Start(url common.URL)
-	// deploy a http api
+	// deploy a http api. The route is identified by restMethodConfig.Path and
+	// restMethodConfig.MethodType together, so the same Path may be deployed
+	// more than once as long as each deployment uses a different MethodType.
 	Deploy(restMethodConfig *rest_config.RestMethodConfig, routeFunc func(request RestServerRequest, response RestServerResponse))
-	// unDeploy a http api
+	// unDeploy a http api, matched by the same Path/MethodType pair used in Deploy
 	UnDeploy(restMethodConfig *rest_config.RestMethodConfig)
 	// destroy rest server
 	Destroy()
@@ -78,6 +80,18 @@ type RestServerResponse interface {
 	WriteEntity(value interface{}) error
 }
 
+// EntityReaderWriter reads request bodies and writes response bodies for one
+// MIME type, making RestServerRequest.ReadEntity and
+// RestServerResponse.WriteEntity negotiate on Content-Type/Accept instead of
+// assuming a single body format. Register one with
+// extension.SetRestEntityAccessor.
+type EntityReaderWriter interface {
+	// Read unmarshals the request body into v.
+	Read(req RestServerRequest, v interface{}) error
+	// Write sets httpStatus and marshals v as the response body.
+	Write(resp RestServerResponse, httpStatus int, v interface{}) error
+}
+
 // A route function will be invoked by http server
 func GetRouteFunc(invoker protocol.Invoker, methodConfig *rest_config.RestMethodConfig) func(req RestServerRequest, resp RestServerResponse) {
 	return func(req RestServerRequest, resp RestServerResponse) {
@@ -85,6 +99,12 @@ func GetRouteFunc(invoker protocol.Invoker, methodConfig *rest_config.RestMethod
 			err  error
 			args []interface{}
 		)
+		if err = checkConsumes(req, resp, methodConfig); err != nil {
+			return
+		}
+		if err = checkProduces(req, resp, methodConfig); err != nil {
+			return
+		}
 		svc := common.ServiceMap.GetService(invoker.GetUrl().Protocol, strings.TrimPrefix(invoker.GetUrl().Path, "/"))
 		// get method
 		method := svc.Method()[methodConfig.MethodName]
@@ -94,7 +114,13 @@ func GetRouteFunc(invoker protocol.Invoker, methodConfig *rest_config.RestMethod
 			argsTypes[0].String() == "[]interface {}" {
 			args = getArgsInterfaceFromRequest(req, methodConfig)
 		} else {
-			args = getArgsFromRequest(req, argsTypes, methodConfig)
+			args, err = getArgsFromRequest(req, argsTypes, methodConfig)
+			if err != nil {
+				if writeErr := resp.WriteError(http.StatusBadRequest, err); writeErr != nil {
+					logger.Errorf("[Go Restful] WriteError error:%v", writeErr)
+				}
+				return
+			}
 		}
 		result := invoker.Invoke(context.Background(), invocation.NewRPCInvocation(methodConfig.MethodName, args, make(map[string]string)))
 		if result.Error() != nil {
@@ -160,145 +186,177 @@ func getArgsInterfaceFromRequest(req RestServerRequest, methodConfig *rest_confi
 	return args
 }
 
-// get arguments from server.RestServerRequest
-func getArgsFromRequest(req RestServerRequest, argsTypes []reflect.Type, methodConfig *rest_config.RestMethodConfig) []interface{} {
+// get arguments from server.RestServerRequest. Returns an error, without
+// invoking the method, if any parameter could not be bound to its arg.
+func getArgsFromRequest(req RestServerRequest, argsTypes []reflect.Type, methodConfig *rest_config.RestMethodConfig) ([]interface{}, error) {
 	argsLength := len(argsTypes)
 	args := make([]interface{}, argsLength)
 	for i, t := range argsTypes {
 		args[i] = reflect.Zero(t).Interface()
 	}
-	assembleArgsFromPathParams(methodConfig, argsLength, argsTypes, req, args)
-	assembleArgsFromQueryParams(methodConfig, argsLength, argsTypes, req, args)
-	assembleArgsFromBody(methodConfig, argsTypes, req, args)
-	assembleArgsFromHeaders(methodConfig, req, argsLength, argsTypes, args)
-	return args
+	if err := assembleArgsFromPathParams(methodConfig, argsLength, argsTypes, req, args); err != nil {
+		return nil, err
+	}
+	if err := assembleArgsFromQueryParams(methodConfig, argsLength, argsTypes, req, args); err != nil {
+		return nil, err
+	}
+	if err := assembleArgsFromBody(methodConfig, argsTypes, req, args); err != nil {
+		return nil, err
+	}
+	if err := assembleArgsFromHeaders(methodConfig, req, argsLength, argsTypes, args); err != nil {
+		return nil, err
+	}
+	return args, nil
 }
 
 // assemble arguments from headers
-func assembleArgsFromHeaders(methodConfig *rest_config.RestMethodConfig, req RestServerRequest, argsLength int, argsTypes []reflect.Type, args []interface{}) {
+func assembleArgsFromHeaders(methodConfig *rest_config.RestMethodConfig, req RestServerRequest, argsLength int, argsTypes []reflect.Type, args []interface{}) error {
 	for k, v := range methodConfig.HeadersMap {
-		param := req.HeaderParameter(v)
 		if k < 0 || k >= argsLength {
-			logger.Errorf("[Go restful] Header param parse error, the args:%v doesn't exist", k)
-			continue
-		}
-		t := argsTypes[k]
-		if t.Kind() == reflect.Ptr {
-			t = t.Elem()
+			return perrors.Errorf("[Go restful] header param %q: arg %v doesn't exist", v, k)
 		}
-		if t.Kind() == reflect.String {
-			args[k] = param
-		} else {
-			logger.Errorf("[Go restful] Header param parse error, the args:%v of type isn't string", k)
+		raw := req.HeaderParameter(v)
+		param, err := convertParam(raw, argsTypes[k], layoutFor(methodConfig, k))
+		if err != nil {
+			return perrors.Errorf("[Go restful] header param %q: arg %v expects %v, got %q", v, k, argsTypes[k], raw)
 		}
+		args[k] = param
 	}
+	return nil
 }
 
 // assemble arguments from body
-func assembleArgsFromBody(methodConfig *rest_config.RestMethodConfig, argsTypes []reflect.Type, req RestServerRequest, args []interface{}) {
-	if methodConfig.Body >= 0 && methodConfig.Body < len(argsTypes) {
-		t := argsTypes[methodConfig.Body]
-		kind := t.Kind()
-		if kind == reflect.Ptr {
-			t = t.Elem()
-		}
-		var ni interface{}
-		if t.String() == "[]interface {}" {
-			ni = make([]map[string]interface{}, 0)
-		} else if t.String() == "interface {}" {
-			ni = make(map[string]interface{})
-		} else {
-			n := reflect.New(t)
-			if n.CanInterface() {
-				ni = n.Interface()
-			}
-		}
-		if err := req.ReadEntity(&ni); err != nil {
-			logger.Errorf("[Go restful] Read body entity error:%v", err)
-		} else {
-			args[methodConfig.Body] = ni
+func assembleArgsFromBody(methodConfig *rest_config.RestMethodConfig, argsTypes []reflect.Type, req RestServerRequest, args []interface{}) error {
+	if methodConfig.Body < 0 || methodConfig.Body >= len(argsTypes) {
+		return nil
+	}
+	t := argsTypes[methodConfig.Body]
+	kind := t.Kind()
+	if kind == reflect.Ptr {
+		t = t.Elem()
+	}
+	var ni interface{}
+	if t.String() == "[]interface {}" {
+		ni = make([]map[string]interface{}, 0)
+	} else if t.String() == "interface {}" {
+		ni = make(map[string]interface{})
+	} else {
+		n := reflect.New(t)
+		if n.CanInterface() {
+			ni = n.Interface()
 		}
 	}
+	if err := req.ReadEntity(&ni); err != nil {
+		return perrors.Errorf("[Go restful] body param: arg %v expects %v, parse error:%v", methodConfig.Body, t, err)
+	}
+	args[methodConfig.Body] = ni
+	return nil
 }
 
 // assemble arguments from query params
-func assembleArgsFromQueryParams(methodConfig *rest_config.RestMethodConfig, argsLength int, argsTypes []reflect.Type, req RestServerRequest, args []interface{}) {
-	var (
-		err   error
-		param interface{}
-		i64   int64
-	)
+func assembleArgsFromQueryParams(methodConfig *rest_config.RestMethodConfig, argsLength int, argsTypes []reflect.Type, req RestServerRequest, args []interface{}) error {
 	for k, v := range methodConfig.QueryParamsMap {
 		if k < 0 || k >= argsLength {
-			logger.Errorf("[Go restful] Query param parse error, the args:%v doesn't exist", k)
-			continue
+			return perrors.Errorf("[Go restful] query param %q: arg %v doesn't exist", v, k)
 		}
 		t := argsTypes[k]
-		kind := t.Kind()
-		if kind == reflect.Ptr {
+		if t.Kind() == reflect.Ptr {
 			t = t.Elem()
 		}
-		if kind == reflect.Slice {
-			param = req.QueryParameters(v)
-		} else if kind == reflect.String {
-			param = req.QueryParameter(v)
-		} else if kind == reflect.Int {
-			param, err = strconv.Atoi(req.QueryParameter(v))
-		} else if kind == reflect.Int32 {
-			i64, err = strconv.ParseInt(req.QueryParameter(v), 10, 32)
-			if err == nil {
-				param = int32(i64)
+		if t.Kind() == reflect.Slice {
+			raws := req.QueryParameters(v)
+			slice := reflect.MakeSlice(t, len(raws), len(raws))
+			for i, raw := range raws {
+				elem, err := convertParam(raw, t.Elem(), layoutFor(methodConfig, k))
+				if err != nil {
+					return perrors.Errorf("[Go restful] query param %q: arg %v expects %v, got %q", v, k, t, raw)
+				}
+				slice.Index(i).Set(reflect.ValueOf(elem))
 			}
-		} else if kind == reflect.Int64 {
-			param, err = strconv.ParseInt(req.QueryParameter(v), 10, 64)
-		} else {
-			logger.Errorf("[Go restful] Query param parse error, the args:%v of type isn't int or string or slice", k)
+			args[k] = slice.Interface()
 			continue
 		}
+		raw := req.QueryParameter(v)
+		param, err := convertParam(raw, t, layoutFor(methodConfig, k))
 		if err != nil {
-			logger.Errorf("[Go restful] Query param parse error, error is %v", err)
-			continue
+			return perrors.Errorf("[Go restful] query param %q: arg %v expects %v, got %q", v, k, t, raw)
 		}
 		args[k] = param
 	}
+	return nil
 }
 
-// assemble arguments from path params
-func assembleArgsFromPathParams(methodConfig *rest_config.RestMethodConfig, argsLength int, argsTypes []reflect.Type, req RestServerRequest, args []interface{}) {
-	var (
-		err   error
-		param interface{}
-		i64   int64
-	)
-	for k, v := range methodConfig.PathParamsMap {
-		if k < 0 || k >= argsLength {
-			logger.Errorf("[Go restful] Path param parse error, the args:%v doesn't exist", k)
-			continue
+// checkConsumes writes a 415 and returns an error if methodConfig.Consumes is
+// set and the request's Content-Type isn't one of its MIME types. A request
+// without a Content-Type header always passes.
+func checkConsumes(req RestServerRequest, resp RestServerResponse, methodConfig *rest_config.RestMethodConfig) error {
+	if methodConfig.Consumes == "" {
+		return nil
+	}
+	contentType := req.RawRequest().Header.Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+	mimeType, _, parseErr := mime.ParseMediaType(contentType)
+	if parseErr != nil {
+		mimeType = contentType
+	}
+	for _, consumed := range strings.Split(methodConfig.Consumes, ",") {
+		if strings.TrimSpace(consumed) == mimeType {
+			return nil
 		}
-		t := argsTypes[k]
-		kind := t.Kind()
-		if kind == reflect.Ptr {
-			t = t.Elem()
+	}
+	err := perrors.Errorf("[Go Restful] unsupported content type %v, method %v only consumes %v",
+		mimeType, methodConfig.MethodName, methodConfig.Consumes)
+	if writeErr := resp.WriteError(http.StatusUnsupportedMediaType, err); writeErr != nil {
+		logger.Errorf("[Go Restful] WriteError error:%v", writeErr)
+	}
+	return err
+}
+
+// checkProduces writes a 406 and returns an error if methodConfig.Produces is
+// set, the request sent an Accept header, and none of methodConfig.Produces'
+// MIME types satisfy it. A missing or "*/*" Accept header always passes,
+// falling through to the first Produces entry when the response is written.
+func checkProduces(req RestServerRequest, resp RestServerResponse, methodConfig *rest_config.RestMethodConfig) error {
+	if methodConfig.Produces == "" {
+		return nil
+	}
+	accept := req.RawRequest().Header.Get("Accept")
+	if accept == "" || accept == "*/*" {
+		return nil
+	}
+	for _, accepted := range strings.Split(accept, ",") {
+		accepted = strings.TrimSpace(strings.Split(accepted, ";")[0])
+		if accepted == "*/*" {
+			return nil
 		}
-		if kind == reflect.Int {
-			param, err = strconv.Atoi(req.PathParameter(v))
-		} else if kind == reflect.Int32 {
-			i64, err = strconv.ParseInt(req.PathParameter(v), 10, 32)
-			if err == nil {
-				param = int32(i64)
+		for _, produced := range strings.Split(methodConfig.Produces, ",") {
+			if strings.TrimSpace(produced) == accepted {
+				return nil
 			}
-		} else if kind == reflect.Int64 {
-			param, err = strconv.ParseInt(req.PathParameter(v), 10, 64)
-		} else if kind == reflect.String {
-			param = req.PathParameter(v)
-		} else {
-			logger.Warnf("[Go restful] Path param parse error, the args:%v of type isn't int or string", k)
-			continue
 		}
+	}
+	err := perrors.Errorf("[Go Restful] unsupported accept %v, method %v only produces %v",
+		accept, methodConfig.MethodName, methodConfig.Produces)
+	if writeErr := resp.WriteError(http.StatusNotAcceptable, err); writeErr != nil {
+		logger.Errorf("[Go Restful] WriteError error:%v", writeErr)
+	}
+	return err
+}
+
+// assemble arguments from path params
+func assembleArgsFromPathParams(methodConfig *rest_config.RestMethodConfig, argsLength int, argsTypes []reflect.Type, req RestServerRequest, args []interface{}) error {
+	for k, v := range methodConfig.PathParamsMap {
+		if k < 0 || k >= argsLength {
+			return perrors.Errorf("[Go restful] path param %q: arg %v doesn't exist", v, k)
+		}
+		raw := req.PathParameter(v)
+		param, err := convertParam(raw, argsTypes[k], layoutFor(methodConfig, k))
 		if err != nil {
-			logger.Errorf("[Go restful] Path param parse error, error is %v", err)
-			continue
+			return perrors.Errorf("[Go restful] path param %q: arg %v expects %v, got %q", v, k, argsTypes[k], raw)
 		}
 		args[k] = param
 	}
+	return nil
 }
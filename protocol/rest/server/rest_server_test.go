@@ -0,0 +1,182 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	rest_config "github.com/apache/dubbo-go/protocol/rest/config"
+)
+
+// fakeRequest is a minimal RestServerRequest backed by plain maps, standing
+// in for GoRestfulRequest in tests that don't need a real HTTP server.
+type fakeRequest struct {
+	raw     *http.Request
+	path    map[string]string
+	query   map[string][]string
+	headers map[string]string
+}
+
+func newFakeRequest() *fakeRequest {
+	return &fakeRequest{
+		raw:     httptest.NewRequest(http.MethodGet, "/", nil),
+		path:    map[string]string{},
+		query:   map[string][]string{},
+		headers: map[string]string{},
+	}
+}
+
+func (r *fakeRequest) RawRequest() *http.Request         { return r.raw }
+func (r *fakeRequest) PathParameter(name string) string  { return r.path[name] }
+func (r *fakeRequest) PathParameters() map[string]string { return r.path }
+func (r *fakeRequest) QueryParameter(name string) string {
+	if vs := r.query[name]; len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+func (r *fakeRequest) QueryParameters(name string) []string { return r.query[name] }
+func (r *fakeRequest) BodyParameter(name string) (string, error) {
+	return "", nil
+}
+func (r *fakeRequest) HeaderParameter(name string) string { return r.headers[name] }
+func (r *fakeRequest) ReadEntity(entityPointer interface{}) error {
+	return nil
+}
+
+// fakeResponse is a minimal RestServerResponse recording what was written,
+// standing in for GoRestfulResponse in tests that don't need a real
+// go-restful response.
+type fakeResponse struct {
+	header      http.Header
+	status      int
+	wroteError  error
+	wroteEntity interface{}
+}
+
+func newFakeResponse() *fakeResponse {
+	return &fakeResponse{header: http.Header{}}
+}
+
+func (r *fakeResponse) Header() http.Header         { return r.header }
+func (r *fakeResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (r *fakeResponse) WriteHeader(statusCode int)  { r.status = statusCode }
+func (r *fakeResponse) WriteError(httpStatus int, err error) error {
+	r.status = httpStatus
+	r.wroteError = err
+	return nil
+}
+func (r *fakeResponse) WriteEntity(value interface{}) error {
+	r.status = http.StatusOK
+	r.wroteEntity = value
+	return nil
+}
+
+// TestGetArgsFromRequestBadQueryParamErrors exercises the same failure
+// getArgsFromRequest reports for a path/header param: GetRouteFunc treats
+// this error as "stop and write a 400", never reaching invoker.Invoke. That
+// branch itself isn't exercised directly here because GetRouteFunc's
+// signature depends on protocol.Invoker/common.ServiceMap, which aren't part
+// of this source tree snapshot; this test covers the part of the behavior
+// that is self-contained, the argument binding that decides whether the 400
+// fires at all.
+func TestGetArgsFromRequestBadQueryParamErrors(t *testing.T) {
+	methodConfig := &rest_config.RestMethodConfig{
+		QueryParamsMap: map[int]string{0: "age"},
+	}
+	req := newFakeRequest()
+	req.query["age"] = []string{"not-a-number"}
+
+	_, err := getArgsFromRequest(req, []reflect.Type{reflect.TypeOf(0)}, methodConfig)
+	assert.Error(t, err)
+}
+
+func TestGetArgsFromRequestBadPathParamErrors(t *testing.T) {
+	methodConfig := &rest_config.RestMethodConfig{
+		PathParamsMap: map[int]string{0: "id"},
+	}
+	req := newFakeRequest()
+	req.path["id"] = "not-a-number"
+
+	_, err := getArgsFromRequest(req, []reflect.Type{reflect.TypeOf(0)}, methodConfig)
+	assert.Error(t, err)
+}
+
+func TestGetArgsFromRequestBadHeaderParamErrors(t *testing.T) {
+	methodConfig := &rest_config.RestMethodConfig{
+		HeadersMap: map[int]string{0: "X-Count"},
+	}
+	req := newFakeRequest()
+	req.headers["X-Count"] = "not-a-number"
+
+	_, err := getArgsFromRequest(req, []reflect.Type{reflect.TypeOf(0)}, methodConfig)
+	assert.Error(t, err)
+}
+
+func TestGetArgsFromRequestValidParamsNoError(t *testing.T) {
+	methodConfig := &rest_config.RestMethodConfig{
+		QueryParamsMap: map[int]string{0: "age"},
+		Body:           -1,
+	}
+	req := newFakeRequest()
+	req.query["age"] = []string{"42"}
+
+	args, err := getArgsFromRequest(req, []reflect.Type{reflect.TypeOf(0)}, methodConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, args[0])
+}
+
+func TestCheckConsumesWrites415OnMismatch(t *testing.T) {
+	methodConfig := &rest_config.RestMethodConfig{Consumes: "application/json"}
+	req := newFakeRequest()
+	req.raw.Header.Set("Content-Type", "application/xml")
+	resp := newFakeResponse()
+
+	err := checkConsumes(req, resp, methodConfig)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.status)
+}
+
+func TestCheckProducesWrites406OnMismatch(t *testing.T) {
+	methodConfig := &rest_config.RestMethodConfig{Produces: "application/xml"}
+	req := newFakeRequest()
+	req.raw.Header.Set("Accept", "application/json")
+	resp := newFakeResponse()
+
+	err := checkProduces(req, resp, methodConfig)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotAcceptable, resp.status)
+}
+
+func TestCheckProducesPassesOnEmptyAccept(t *testing.T) {
+	methodConfig := &rest_config.RestMethodConfig{Produces: "application/xml"}
+	req := newFakeRequest()
+	resp := newFakeResponse()
+
+	assert.NoError(t, checkProduces(req, resp, methodConfig))
+	assert.Zero(t, resp.status)
+}
@@ -0,0 +1,175 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server_impl
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/apache/dubbo-go/protocol/rest/server"
+)
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} { return gzip.NewWriter(ioutil.Discard) },
+}
+
+var flateWriterPool = sync.Pool{
+	New: func() interface{} {
+		w, _ := flate.NewWriter(ioutil.Discard, flate.DefaultCompression)
+		return w
+	},
+}
+
+// flateWriter is the subset of gzip.Writer and flate.Writer's API
+// CompressingResponseWriter needs, letting both share one code path.
+type flateWriter interface {
+	io.WriteCloser
+	Reset(dst io.Writer)
+}
+
+// CompressingResponseWriter wraps a server.RestServerResponse, transparently
+// gzip- or deflate-encoding the body when the client's Accept-Encoding allows
+// it and the body is at least minSize bytes. Writes are buffered so the size
+// can be checked before any bytes reach the client; Close must always run
+// (typically via defer) so the pooled compressor is returned and a body that
+// errors mid-write doesn't leak its buffer.
+//
+// WriteEntity, WriteError and WriteHeader are overridden, not just promoted
+// from the embedded server.RestServerResponse: a method promoted from an
+// embedded interface is bound to that embedded value, not to this wrapper, so
+// without these overrides a caller going through the server.RestServerResponse
+// interface (as GetRouteFunc does) would write straight past the buffer and
+// compression would never run. WriteHeader in particular must be deferred to
+// Close: per net/http's ResponseWriter contract, headers are frozen the
+// moment WriteHeader runs, so if it forwarded immediately the
+// Content-Encoding/Content-Length set afterwards in Close would never reach
+// the client, while the body they describe would already be compressed.
+type CompressingResponseWriter struct {
+	server.RestServerResponse
+	acceptEncoding string
+	// accept and produces are the request's Accept header and the deployed
+	// method's RestMethodConfig.Produces, passed through to writerFor so
+	// WriteEntity picks the same server.EntityReaderWriter GoRestfulResponse
+	// would have.
+	accept   string
+	produces string
+	minSize  int
+	buf      bytes.Buffer
+	// statusCode is the status WriteHeader was called with, applied to the
+	// underlying response in Close once Content-Encoding/Content-Length have
+	// been decided.
+	statusCode int
+}
+
+// defaultMinCompressSize is used when minSize <= 0.
+const defaultMinCompressSize = 1024
+
+// NewCompressingResponseWriter wraps resp. acceptEncoding is the request's
+// Accept-Encoding header, accept and produces are forwarded to writerFor for
+// WriteEntity's content negotiation, and minSize is the minimum body size
+// worth compressing (<= 0 uses defaultMinCompressSize).
+func NewCompressingResponseWriter(resp server.RestServerResponse, acceptEncoding, accept, produces string, minSize int) *CompressingResponseWriter {
+	if minSize <= 0 {
+		minSize = defaultMinCompressSize
+	}
+	return &CompressingResponseWriter{
+		RestServerResponse: resp,
+		acceptEncoding:     acceptEncoding,
+		accept:             accept,
+		produces:           produces,
+		minSize:            minSize,
+		statusCode:         http.StatusOK,
+	}
+}
+
+// Write buffers b; the real write happens in Close once the final size is known.
+func (w *CompressingResponseWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
+
+// WriteHeader stashes statusCode; it's applied to the underlying response in
+// Close, after Content-Encoding/Content-Length have been set, instead of
+// being forwarded immediately.
+func (w *CompressingResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+}
+
+// WriteEntity marshals value through the same server.EntityReaderWriter
+// GoRestfulResponse.WriteEntity would pick, writing it via w.Write so it's
+// buffered and eligible for compression in Close.
+func (w *CompressingResponseWriter) WriteEntity(value interface{}) error {
+	return writerFor(w.accept, w.produces).Write(w, http.StatusOK, value)
+}
+
+// WriteError writes httpStatus and err's message via w.Write so, like
+// WriteEntity, the error body is buffered and eligible for compression.
+func (w *CompressingResponseWriter) WriteError(httpStatus int, err error) error {
+	w.WriteHeader(httpStatus)
+	if err == nil {
+		return nil
+	}
+	_, writeErr := w.Write([]byte(err.Error()))
+	return writeErr
+}
+
+// Close flushes the buffered body to the underlying response, compressing it
+// first if it's large enough and the client accepts gzip or deflate. It must
+// be called exactly once after the handler is done writing.
+func (w *CompressingResponseWriter) Close() error {
+	if w.buf.Len() < w.minSize {
+		w.RestServerResponse.WriteHeader(w.statusCode)
+		_, err := w.RestServerResponse.Write(w.buf.Bytes())
+		return err
+	}
+	switch {
+	case strings.Contains(w.acceptEncoding, "gzip"):
+		fw := gzipWriterPool.Get().(*gzip.Writer)
+		defer gzipWriterPool.Put(fw)
+		return w.writeCompressed("gzip", fw)
+	case strings.Contains(w.acceptEncoding, "deflate"):
+		fw := flateWriterPool.Get().(*flate.Writer)
+		defer flateWriterPool.Put(fw)
+		return w.writeCompressed("deflate", fw)
+	default:
+		w.RestServerResponse.WriteHeader(w.statusCode)
+		_, err := w.RestServerResponse.Write(w.buf.Bytes())
+		return err
+	}
+}
+
+// writeCompressed sets the headers describing the compressed body, then
+// freezes them with WriteHeader before any body bytes are written.
+func (w *CompressingResponseWriter) writeCompressed(encoding string, fw flateWriter) error {
+	w.RestServerResponse.Header().Set("Content-Encoding", encoding)
+	w.RestServerResponse.Header().Del("Content-Length")
+	w.RestServerResponse.WriteHeader(w.statusCode)
+	fw.Reset(w.RestServerResponse)
+	if _, err := fw.Write(w.buf.Bytes()); err != nil {
+		_ = fw.Close()
+		return err
+	}
+	return fw.Close()
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server_impl
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRestServerResponse is a minimal server.RestServerResponse backed by an
+// httptest.ResponseRecorder, standing in for GoRestfulResponse in tests.
+type fakeRestServerResponse struct {
+	*httptest.ResponseRecorder
+}
+
+func (f *fakeRestServerResponse) WriteError(httpStatus int, err error) error {
+	f.WriteHeader(httpStatus)
+	if err == nil {
+		return nil
+	}
+	_, writeErr := f.Write([]byte(err.Error()))
+	return writeErr
+}
+
+func (f *fakeRestServerResponse) WriteEntity(value interface{}) error {
+	return writerFor("", "").Write(f, 200, value)
+}
+
+func newFakeRestServerResponse() *fakeRestServerResponse {
+	return &fakeRestServerResponse{ResponseRecorder: httptest.NewRecorder()}
+}
+
+func TestCompressingResponseWriterPassesThroughSmallBody(t *testing.T) {
+	underlying := newFakeRestServerResponse()
+	w := NewCompressingResponseWriter(underlying, "gzip", "", "", 1024)
+
+	_, err := w.Write([]byte("short"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, "short", underlying.Body.String())
+	assert.Empty(t, underlying.Result().Header.Get("Content-Encoding"))
+}
+
+func TestCompressingResponseWriterGzipsLargeBody(t *testing.T) {
+	underlying := newFakeRestServerResponse()
+	w := NewCompressingResponseWriter(underlying, "gzip", "", "", 16)
+
+	body := strings.Repeat("a", 1024)
+	_, err := w.Write([]byte(body))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	// Asserting on Result().Header, not the live Header() map, matters here:
+	// Result() is the header snapshot net/http freezes at WriteHeader time,
+	// i.e. what a real client would actually receive. Close sets
+	// Content-Encoding/Content-Length before calling the real WriteHeader,
+	// so this only passes if WriteHeader is correctly deferred until then.
+	assert.Equal(t, "gzip", underlying.Result().Header.Get("Content-Encoding"))
+	assert.Empty(t, underlying.Result().Header.Get("Content-Length"))
+
+	gr, err := gzip.NewReader(underlying.Body)
+	assert.NoError(t, err)
+	got, err := ioutil.ReadAll(gr)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(got))
+}
+
+// TestCompressingResponseWriterWriteEntityCompresses is a regression test for
+// WriteEntity writing straight through the wrapped server.RestServerResponse
+// instead of through this writer's own Write/buffer: before the fix, the
+// promoted WriteEntity bypassed compression entirely for every real RPC
+// response, making EnableContentEncoding a no-op.
+func TestCompressingResponseWriterWriteEntityCompresses(t *testing.T) {
+	underlying := newFakeRestServerResponse()
+	w := NewCompressingResponseWriter(underlying, "gzip", "application/json", "", 16)
+
+	value := map[string]string{"data": strings.Repeat("b", 1024)}
+	assert.NoError(t, w.WriteEntity(value))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, "gzip", underlying.Result().Header.Get("Content-Encoding"))
+
+	gr, err := gzip.NewReader(underlying.Body)
+	assert.NoError(t, err)
+	got, err := ioutil.ReadAll(gr)
+	assert.NoError(t, err)
+
+	var decoded map[string]string
+	assert.NoError(t, json.Unmarshal(got, &decoded))
+	assert.Equal(t, value, decoded)
+}
+
+// TestCompressingResponseWriterWriteErrorCompresses exercises WriteError the
+// same way: the status and a large error message must still end up gzipped,
+// with Content-Encoding surviving into the frozen header snapshot.
+func TestCompressingResponseWriterWriteErrorCompresses(t *testing.T) {
+	underlying := newFakeRestServerResponse()
+	w := NewCompressingResponseWriter(underlying, "gzip", "", "", 16)
+
+	msg := strings.Repeat("e", 1024)
+	assert.NoError(t, w.WriteError(http.StatusBadRequest, errors.New(msg)))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, http.StatusBadRequest, underlying.Result().StatusCode)
+	assert.Equal(t, "gzip", underlying.Result().Header.Get("Content-Encoding"))
+
+	gr, err := gzip.NewReader(underlying.Body)
+	assert.NoError(t, err)
+	got, err := ioutil.ReadAll(gr)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, string(got))
+}
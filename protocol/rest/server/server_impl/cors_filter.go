@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server_impl
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+import (
+	"github.com/emicklei/go-restful/v3"
+)
+
+import (
+	"github.com/apache/dubbo-go/common/logger"
+	rest_config "github.com/apache/dubbo-go/protocol/rest/config"
+)
+
+// corsOriginMatcher decides whether an Origin header is allowed by a
+// CorsConfig's AllowedDomains.
+type corsOriginMatcher struct {
+	allowAll bool
+	patterns []*regexp.Regexp
+}
+
+func newCorsOriginMatcher(domains []string) *corsOriginMatcher {
+	m := &corsOriginMatcher{}
+	for _, domain := range domains {
+		if domain == "*" {
+			m.allowAll = true
+			continue
+		}
+		re, err := regexp.Compile("^" + domain + "$")
+		if err != nil {
+			logger.Errorf("[Go Restful] invalid CORS AllowedDomains pattern %v:%v", domain, err)
+			continue
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m
+}
+
+func (m *corsOriginMatcher) allows(origin string) bool {
+	if m.allowAll {
+		return true
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCorsFilter builds a restful.FilterFunction enforcing cfg: it answers
+// preflight OPTIONS requests, adds Access-Control-* headers to responses for
+// allowed origins, and rejects other origins with 403.
+func NewCorsFilter(cfg *rest_config.CorsConfig) restful.FilterFunction {
+	matcher := newCorsOriginMatcher(cfg.AllowedDomains)
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		origin := req.Request.Header.Get("Origin")
+		if origin == "" {
+			chain.ProcessFilter(req, resp)
+			return
+		}
+		if !matcher.allows(origin) {
+			resp.WriteErrorString(http.StatusForbidden, "origin not allowed")
+			return
+		}
+
+		header := resp.Header()
+		header.Set("Access-Control-Allow-Origin", origin)
+		if cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if len(cfg.ExposeHeaders) > 0 {
+			header.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposeHeaders, ", "))
+		}
+
+		if req.Request.Method != http.MethodOptions {
+			chain.ProcessFilter(req, resp)
+			return
+		}
+		if len(cfg.AllowedMethods) > 0 {
+			header.Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+		}
+		if len(cfg.AllowedHeaders) > 0 {
+			header.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+		}
+		if cfg.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+		}
+		resp.WriteHeader(http.StatusOK)
+	}
+}
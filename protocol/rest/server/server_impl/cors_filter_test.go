@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server_impl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	rest_config "github.com/apache/dubbo-go/protocol/rest/config"
+)
+
+func TestCorsFilterAllowsOriginAndAnswersPreflight(t *testing.T) {
+	filter := NewCorsFilter(&rest_config.CorsConfig{
+		AllowedDomains: []string{"*"},
+		AllowedMethods: []string{"POST"},
+		AllowedHeaders: []string{"X-Custom"},
+		MaxAge:         600,
+	})
+
+	httpReq := httptest.NewRequest(http.MethodOptions, "/", nil)
+	httpReq.Header.Set("Origin", "https://example.com")
+	recorder := httptest.NewRecorder()
+	req := restful.NewRequest(httpReq)
+	resp := restful.NewResponse(recorder)
+
+	targetCalled := false
+	chain := &restful.FilterChain{
+		Filters: []restful.FilterFunction{filter},
+		Target: func(req *restful.Request, resp *restful.Response) {
+			targetCalled = true
+		},
+	}
+	chain.ProcessFilter(req, resp)
+
+	assert.False(t, targetCalled, "a preflight request must be answered by the filter, not reach the route")
+	result := recorder.Result()
+	assert.Equal(t, "https://example.com", result.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "POST", result.Header.Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "X-Custom", result.Header.Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", result.Header.Get("Access-Control-Max-Age"))
+}
+
+func TestCorsFilterRejectsDisallowedOrigin(t *testing.T) {
+	filter := NewCorsFilter(&rest_config.CorsConfig{AllowedDomains: []string{"example\\.com"}})
+
+	httpReq := httptest.NewRequest(http.MethodGet, "/", nil)
+	httpReq.Header.Set("Origin", "https://evil.com")
+	recorder := httptest.NewRecorder()
+	req := restful.NewRequest(httpReq)
+	resp := restful.NewResponse(recorder)
+
+	chain := &restful.FilterChain{
+		Filters: []restful.FilterFunction{filter},
+		Target: func(req *restful.Request, resp *restful.Response) {
+			t.Fatal("route must not run for a disallowed origin")
+		},
+	}
+	chain.ProcessFilter(req, resp)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+}
+
+// TestCorsFilterServiceLevelPreemptsRouteLevel is a regression/documentation
+// test for GoRestfulServer.EnableCors and RestMethodConfig.Cors being
+// mutually exclusive on the same route: a service-level filter (installed on
+// the restful.WebService via EnableCors) runs ahead of any route-level
+// filter (installed via RestMethodConfig.Cors), and answers a preflight
+// request itself without calling chain.ProcessFilter - so the route-level
+// filter chained after it never runs, and its AllowedMethods/AllowedHeaders
+// never get applied.
+func TestCorsFilterServiceLevelPreemptsRouteLevel(t *testing.T) {
+	serviceFilter := NewCorsFilter(&rest_config.CorsConfig{AllowedDomains: []string{"*"}})
+	routeFilter := NewCorsFilter(&rest_config.CorsConfig{
+		AllowedDomains: []string{"*"},
+		AllowedMethods: []string{"POST"},
+	})
+
+	httpReq := httptest.NewRequest(http.MethodOptions, "/", nil)
+	httpReq.Header.Set("Origin", "https://example.com")
+	recorder := httptest.NewRecorder()
+	req := restful.NewRequest(httpReq)
+	resp := restful.NewResponse(recorder)
+
+	routeFilterCalled := false
+	chain := &restful.FilterChain{
+		Filters: []restful.FilterFunction{
+			serviceFilter,
+			func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+				routeFilterCalled = true
+				routeFilter(req, resp, chain)
+			},
+		},
+		Target: func(req *restful.Request, resp *restful.Response) {},
+	}
+	chain.ProcessFilter(req, resp)
+
+	assert.False(t, routeFilterCalled)
+	assert.Empty(t, recorder.Result().Header.Get("Access-Control-Allow-Methods"))
+}
@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server_impl
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+import (
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/protocol/rest/server"
+)
+
+// MIME types with a built-in server.EntityReaderWriter. application/x-protobuf,
+// application/x-www-form-urlencoded and multipart/form-data have no default
+// accessor yet; register one with extension.SetRestEntityAccessor to support
+// them.
+const (
+	MIMEJSON = "application/json"
+	MIMEXML  = "application/xml"
+)
+
+func init() {
+	extension.SetRestEntityAccessor(MIMEJSON, &jsonEntityAccessor{})
+	extension.SetRestEntityAccessor(MIMEXML, &xmlEntityAccessor{})
+}
+
+type jsonEntityAccessor struct{}
+
+func (jsonEntityAccessor) Read(req server.RestServerRequest, v interface{}) error {
+	return json.NewDecoder(req.RawRequest().Body).Decode(v)
+}
+
+func (jsonEntityAccessor) Write(resp server.RestServerResponse, httpStatus int, v interface{}) error {
+	resp.Header().Set("Content-Type", MIMEJSON)
+	resp.WriteHeader(httpStatus)
+	return json.NewEncoder(resp).Encode(v)
+}
+
+type xmlEntityAccessor struct{}
+
+func (xmlEntityAccessor) Read(req server.RestServerRequest, v interface{}) error {
+	return xml.NewDecoder(req.RawRequest().Body).Decode(v)
+}
+
+func (xmlEntityAccessor) Write(resp server.RestServerResponse, httpStatus int, v interface{}) error {
+	resp.Header().Set("Content-Type", MIMEXML)
+	resp.WriteHeader(httpStatus)
+	return xml.NewEncoder(resp).Encode(v)
+}
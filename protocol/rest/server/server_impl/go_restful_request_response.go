@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server_impl
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+import (
+	"github.com/emicklei/go-restful/v3"
+)
+
+import (
+	"github.com/apache/dubbo-go/common/extension"
+	"github.com/apache/dubbo-go/protocol/rest/server"
+)
+
+// GoRestfulRequest adapts a *restful.Request to server.RestServerRequest.
+type GoRestfulRequest struct {
+	request *restful.Request
+}
+
+// NewGoRestfulRequest wraps req as a server.RestServerRequest.
+func NewGoRestfulRequest(req *restful.Request) *GoRestfulRequest {
+	return &GoRestfulRequest{request: req}
+}
+
+func (r *GoRestfulRequest) RawRequest() *http.Request {
+	return r.request.Request
+}
+
+func (r *GoRestfulRequest) PathParameter(name string) string {
+	return r.request.PathParameter(name)
+}
+
+func (r *GoRestfulRequest) PathParameters() map[string]string {
+	return r.request.PathParameters()
+}
+
+func (r *GoRestfulRequest) QueryParameter(name string) string {
+	return r.request.QueryParameter(name)
+}
+
+func (r *GoRestfulRequest) QueryParameters(name string) []string {
+	return r.request.QueryParameters(name)
+}
+
+func (r *GoRestfulRequest) BodyParameter(name string) (string, error) {
+	return r.request.BodyParameter(name)
+}
+
+func (r *GoRestfulRequest) HeaderParameter(name string) string {
+	return r.request.HeaderParameter(name)
+}
+
+// ReadEntity picks the server.EntityReaderWriter registered for the request's
+// Content-Type (defaulting to JSON when the header is absent or unknown) and
+// uses it to unmarshal the body into entityPointer.
+func (r *GoRestfulRequest) ReadEntity(entityPointer interface{}) error {
+	return readerFor(r.request.Request.Header.Get("Content-Type")).Read(r, entityPointer)
+}
+
+func readerFor(contentType string) server.EntityReaderWriter {
+	mimeType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mimeType == "" {
+		mimeType = MIMEJSON
+	}
+	if accessor := extension.GetRestEntityAccessor(mimeType); accessor != nil {
+		return accessor
+	}
+	return extension.GetRestEntityAccessor(MIMEJSON)
+}
+
+// GoRestfulResponse adapts a *restful.Response to server.RestServerResponse.
+type GoRestfulResponse struct {
+	response *restful.Response
+	// accept is the request's Accept header, used by WriteEntity to pick the
+	// server.EntityReaderWriter to write with.
+	accept string
+	// produces is the deployed method's RestMethodConfig.Produces, whose
+	// first entry WriteEntity falls back to when accept doesn't match
+	// anything registered.
+	produces string
+}
+
+// NewGoRestfulResponse wraps resp as a server.RestServerResponse. accept is
+// the originating request's Accept header and produces the deployed
+// method's RestMethodConfig.Produces.
+func NewGoRestfulResponse(resp *restful.Response, accept, produces string) *GoRestfulResponse {
+	return &GoRestfulResponse{response: resp, accept: accept, produces: produces}
+}
+
+func (r *GoRestfulResponse) Header() http.Header {
+	return r.response.Header()
+}
+
+func (r *GoRestfulResponse) Write(b []byte) (int, error) {
+	return r.response.Write(b)
+}
+
+func (r *GoRestfulResponse) WriteHeader(statusCode int) {
+	r.response.WriteHeader(statusCode)
+}
+
+func (r *GoRestfulResponse) WriteError(httpStatus int, err error) error {
+	return r.response.WriteError(httpStatus, err)
+}
+
+// WriteEntity picks the server.EntityReaderWriter matching the first MIME
+// type in the Accept header that has one registered, falling back to the
+// first entry of produces when Accept is absent, "*/*", or matches nothing
+// registered, and to JSON if that doesn't resolve either.
+func (r *GoRestfulResponse) WriteEntity(value interface{}) error {
+	return writerFor(r.accept, r.produces).Write(r, http.StatusOK, value)
+}
+
+// writerFor picks the server.EntityReaderWriter for the first MIME type in
+// accept that has one registered. If none match, it falls back to the first
+// entry of produces (a RestMethodConfig.Produces), and finally to JSON.
+func writerFor(accept, produces string) server.EntityReaderWriter {
+	for _, accepted := range strings.Split(accept, ",") {
+		mimeType := strings.TrimSpace(strings.Split(accepted, ";")[0])
+		if mimeType == "" || mimeType == "*/*" {
+			continue
+		}
+		if accessor := extension.GetRestEntityAccessor(mimeType); accessor != nil {
+			return accessor
+		}
+	}
+	if produces != "" {
+		first := strings.TrimSpace(strings.Split(produces, ",")[0])
+		if accessor := extension.GetRestEntityAccessor(first); accessor != nil {
+			return accessor
+		}
+	}
+	return extension.GetRestEntityAccessor(MIMEJSON)
+}
+
+var _ server.RestServerRequest = (*GoRestfulRequest)(nil)
+var _ server.RestServerResponse = (*GoRestfulResponse)(nil)
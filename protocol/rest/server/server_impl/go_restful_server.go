@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server_impl
+
+import (
+	"net/http"
+	"sync"
+)
+
+import (
+	"github.com/emicklei/go-restful/v3"
+)
+
+import (
+	"github.com/apache/dubbo-go/common"
+	"github.com/apache/dubbo-go/common/logger"
+	rest_config "github.com/apache/dubbo-go/protocol/rest/config"
+	"github.com/apache/dubbo-go/protocol/rest/server"
+)
+
+// GoRestfulServer is a server.RestServer implementation backed by
+// emicklei/go-restful. Routes are keyed by RestMethodConfig.RouteKey(), i.e.
+// by MethodType+Path, so the same Path can be deployed by more than one HTTP
+// verb at once.
+type GoRestfulServer struct {
+	srv       *http.Server
+	container *restful.Container
+	ws        *restful.WebService
+
+	mutex  sync.Mutex
+	routes map[string]bool
+}
+
+// NewGoRestfulServer creates a GoRestfulServer ready to Deploy routes on.
+func NewGoRestfulServer() *GoRestfulServer {
+	ws := new(restful.WebService)
+	container := restful.NewContainer()
+	container.Add(ws)
+	return &GoRestfulServer{
+		container: container,
+		ws:        ws,
+		routes:    make(map[string]bool, 8),
+	}
+}
+
+// Start starts the underlying http.Server listening on url.Location.
+func (g *GoRestfulServer) Start(url common.URL) {
+	g.srv = &http.Server{
+		Addr:    url.Location,
+		Handler: g.container,
+	}
+	go func() {
+		if err := g.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("[Go Restful] Server start error:%v", err)
+		}
+	}()
+}
+
+// Deploy registers routeFunc on methodConfig.Path for methodConfig.MethodType.
+// Deploying the same Path again with a different MethodType adds a second
+// route rather than replacing the first one.
+func (g *GoRestfulServer) Deploy(methodConfig *rest_config.RestMethodConfig, routeFunc func(req server.RestServerRequest, resp server.RestServerResponse)) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	key := methodConfig.RouteKey()
+	if g.routes[key] {
+		logger.Warnf("[Go Restful] Route %v is already deployed, skip", key)
+		return
+	}
+	g.routes[key] = true
+
+	rb := g.ws.Method(methodConfig.HTTPMethod()).Path(methodConfig.Path)
+	if methodConfig.Cors != nil {
+		rb = rb.Filter(NewCorsFilter(methodConfig.Cors))
+	}
+	rb = rb.To(func(req *restful.Request, resp *restful.Response) {
+		accept := req.HeaderParameter("Accept")
+		serverResp := server.RestServerResponse(NewGoRestfulResponse(resp, accept, methodConfig.Produces))
+		if methodConfig.EnableContentEncoding {
+			compressing := NewCompressingResponseWriter(serverResp, req.HeaderParameter("Accept-Encoding"), accept, methodConfig.Produces, methodConfig.MinCompressSize)
+			defer func() {
+				if err := compressing.Close(); err != nil {
+					logger.Errorf("[Go Restful] CompressingResponseWriter close error:%v", err)
+				}
+			}()
+			serverResp = compressing
+		}
+		routeFunc(NewGoRestfulRequest(req), serverResp)
+	})
+	g.ws.Route(rb)
+}
+
+// EnableCors installs cfg as a CORS filter applying to every route deployed
+// on this server. It is not meant to be combined with a per-method
+// RestMethodConfig.Cors: this filter runs ahead of any route-level filter,
+// and answers preflight OPTIONS requests itself without continuing the
+// filter chain, so a route's own Cors (and its AllowedMethods/AllowedHeaders)
+// never gets a chance to run on a preflight request once this is installed.
+// Use either this, for a
+// single CORS policy shared by the whole server, or per-method Cors, not
+// both on the same route.
+func (g *GoRestfulServer) EnableCors(cfg *rest_config.CorsConfig) {
+	g.ws.Filter(NewCorsFilter(cfg))
+}
+
+// UnDeploy removes the route identified by methodConfig.Path/MethodType.
+func (g *GoRestfulServer) UnDeploy(methodConfig *rest_config.RestMethodConfig) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	key := methodConfig.RouteKey()
+	if !g.routes[key] {
+		return
+	}
+	delete(g.routes, key)
+
+	if err := g.ws.RemoveRoute(methodConfig.Path, methodConfig.HTTPMethod()); err != nil {
+		logger.Errorf("[Go Restful] UnDeploy route %v error:%v", key, err)
+	}
+}
+
+// Destroy closes the underlying http.Server.
+func (g *GoRestfulServer) Destroy() {
+	if g.srv != nil {
+		if err := g.srv.Close(); err != nil {
+			logger.Errorf("[Go Restful] Server close error:%v", err)
+		}
+	}
+}